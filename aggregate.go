@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// aggregateParams is the shared input to fetchAggregation, which the
+// summary, diff, and trend subcommands all drive with different windows.
+type aggregateParams struct {
+	ctx                  context.Context
+	cfg                  Config
+	account              string
+	apiMode              string
+	concurrency          int
+	includeContributions bool
+	since, until         time.Time
+	onProgress           func(done, total int)
+	useCache             bool
+}
+
+type aggregateResult struct {
+	Languages Languages
+	Repos     LanguagesList
+	RepoCount int
+	RateLimit *rateLimitStatus
+}
+
+// cacheVariant hashes every parameter besides account/since/until that
+// changes what fetchAggregation puts in a snapshot - apiMode,
+// includeContributions, include_forks, and the exclude lists - so
+// snapshotPath never hands back a cache hit from a run made under
+// different settings.
+func cacheVariant(p aggregateParams) string {
+	excludeRepos := append([]string(nil), p.cfg.ExcludeRepos...)
+	sort.Strings(excludeRepos)
+
+	h := sha256.New()
+	h.Write([]byte(p.apiMode))
+	h.Write([]byte(strconv.FormatBool(p.includeContributions)))
+	h.Write([]byte(strconv.FormatBool(p.cfg.IncludeForks)))
+	h.Write([]byte(strings.Join(excludeRepos, ",")))
+	return hex.EncodeToString(h.Sum(nil))[:8]
+}
+
+// fetchAggregation runs the full repo-discovery -> language-fetch pipeline
+// for a single account and time window, reusing a cached snapshot (see
+// cache.go) when useCache is set and one already exists for this exact
+// account/window/settings combination.
+func fetchAggregation(p aggregateParams) (*aggregateResult, error) {
+	var path string
+	if p.useCache {
+		var err error
+		if path, err = snapshotPath(p.account, p.since, p.until, cacheVariant(p)); err == nil {
+			if snap, ok := loadSnapshot(path); ok {
+				return &aggregateResult{
+					Languages: sumLanguages(snap.Results, p.cfg.ExcludeLanguages),
+					Repos:     snap.Results,
+					RepoCount: snap.RepoCount,
+				}, nil
+			}
+		}
+	}
+
+	rateLimit := newRateLimitStatus()
+	etagTransport := newETagCachingTransport(http.DefaultTransport)
+	transport := newRateLimitTransport(etagTransport, rateLimit)
+	defer func() { _ = etagTransport.flush() }()
+
+	client, err := api.NewRESTClient(api.ClientOptions{Transport: transport})
+	if err != nil {
+		return nil, err
+	}
+	graphqlClient, err := api.NewGraphQLClient(api.ClientOptions{Transport: transport})
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := getRepositories(p.ctx, client, p.account)
+	if err != nil {
+		return nil, err
+	}
+	repos = filterRepositories(repos, p.since, p.until, p.cfg.ExcludeRepos, p.cfg.IncludeForks)
+
+	var results LanguagesList
+	if p.apiMode == "graphql" {
+		results, err = getLanguagesGraphQL(p.ctx, graphqlClient, repos)
+	} else {
+		results, err = getLanguages(p.ctx, client, repos, p.concurrency, p.onProgress)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.includeContributions {
+		since, until := p.since, p.until
+		if since.IsZero() {
+			since = time.Now().AddDate(-1, 0, 0)
+		}
+		if until.IsZero() {
+			until = time.Now()
+		}
+
+		contributed, err := getContributedRepos(p.ctx, graphqlClient, client, p.account, since, until)
+		if err != nil {
+			return nil, err
+		}
+
+		owned := make(map[string]bool, len(repos))
+		for _, repo := range repos {
+			owned[repo.GetFullName()] = true
+		}
+		external := contributed[:0]
+		for _, repo := range contributed {
+			if owned[repo.FullName] {
+				continue
+			}
+			if matchesAny(p.cfg.ExcludeRepos, repo.Name) || matchesAny(p.cfg.ExcludeRepos, repo.FullName) {
+				continue
+			}
+			external = append(external, repo)
+		}
+
+		if external, err = getTotalCommitCounts(p.ctx, graphqlClient, external, since, until); err != nil {
+			return nil, err
+		}
+
+		contributionResults, err := getContributionLanguages(p.ctx, client, external, p.concurrency)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, contributionResults...)
+	}
+
+	if p.useCache && path != "" {
+		_ = saveSnapshot(path, snapshot{
+			Account:   p.account,
+			Since:     p.since,
+			Until:     p.until,
+			FetchedAt: time.Now(),
+			RepoCount: len(repos),
+			Results:   results,
+		})
+	}
+
+	return &aggregateResult{
+		Languages: sumLanguages(results, p.cfg.ExcludeLanguages),
+		Repos:     results,
+		RepoCount: len(repos),
+		RateLimit: rateLimit,
+	}, nil
+}
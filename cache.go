@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshot is a persisted aggregation run, cached so diff/trend can reuse
+// prior fetches for the same account and window instead of re-hitting the
+// API every time they're invoked.
+type snapshot struct {
+	Account   string        `json:"account"`
+	Since     time.Time     `json:"since"`
+	Until     time.Time     `json:"until"`
+	FetchedAt time.Time     `json:"fetched_at"`
+	RepoCount int           `json:"repo_count"`
+	Results   LanguagesList `json:"results"`
+}
+
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-langs"), nil
+}
+
+// snapshotPath keys a cache entry by account, window, and variant (a short
+// hash of every other parameter that changes what fetchAggregation puts in
+// the snapshot - see cacheVariant), so two runs over the same window but
+// with a different --api, --include-contributions, profile, or exclude
+// list don't collide on the same file.
+func snapshotPath(account string, since, until time.Time, variant string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s_%s_%s_%s.json", account, since.Format("20060102T150405"), until.Format("20060102T150405"), variant)
+	return filepath.Join(dir, name), nil
+}
+
+func loadSnapshot(path string) (snapshot, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot{}, false
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return snapshot{}, false
+	}
+	return snap, true
+}
+
+func saveSnapshot(path string, snap snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a named set of flag defaults a user can select with
+// --profile, e.g. a "work" profile pointing at a different account.
+type Profile struct {
+	Account      string  `yaml:"account"`
+	Filter       float64 `yaml:"filter"`
+	Concurrency  int     `yaml:"concurrency"`
+	Format       string  `yaml:"format"`
+	IncludeForks *bool   `yaml:"include_forks"`
+}
+
+// Config is the persisted contents of ~/.config/gh-langs/config.yaml.
+type Config struct {
+	Filter           float64            `yaml:"filter"`
+	Concurrency      int                `yaml:"concurrency"`
+	Format           string             `yaml:"format"`
+	IncludeForks     bool               `yaml:"include_forks"`
+	ExcludeLanguages []string           `yaml:"exclude_languages"`
+	ExcludeRepos     []string           `yaml:"exclude_repos"`
+	Profiles         map[string]Profile `yaml:"profiles"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Filter:           1.0,
+		Concurrency:      8,
+		Format:           "table",
+		IncludeForks:     true,
+		ExcludeLanguages: []string{},
+		ExcludeRepos:     []string{},
+	}
+}
+
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-langs", "config.yaml"), nil
+}
+
+// loadConfig reads ~/.config/gh-langs/config.yaml, writing it out with
+// sensible defaults the first time it's missing.
+func loadConfig() (Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return Config{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		cfg := defaultConfig()
+		return cfg, writeConfig(path, cfg)
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := defaultConfig()
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func writeConfig(path string, cfg Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// applyProfile layers a named profile's non-zero values on top of the base
+// config, the same way CLI flags later layer on top of the result.
+func applyProfile(cfg Config, profile Profile) Config {
+	if profile.Filter != 0 {
+		cfg.Filter = profile.Filter
+	}
+	if profile.Concurrency != 0 {
+		cfg.Concurrency = profile.Concurrency
+	}
+	if profile.Format != "" {
+		cfg.Format = profile.Format
+	}
+	if profile.IncludeForks != nil {
+		cfg.IncludeForks = *profile.IncludeForks
+	}
+	return cfg
+}
+
+// matchesAny reports whether name matches any of the given glob patterns,
+// used for both exclude_repos and exclude_languages.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, err := filepath.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
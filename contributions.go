@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// contributedRepo is an external repo the account has committed to, along
+// with enough commit-count information to weight its languages by the
+// account's share of the activity rather than crediting it the whole repo.
+type contributedRepo struct {
+	Owner        string
+	Name         string
+	FullName     string
+	UserCommits  int
+	TotalCommits int // 0 means unknown; the user is treated as the sole contributor
+}
+
+// getContributedRepos discovers repos the account has committed to outside
+// of the repos it owns, during [since, until), via the GraphQL
+// contributionsCollection. If that call fails (missing scopes, GHES
+// version, etc.) it falls back to the REST commit search endpoint, which
+// can't report a total-commits denominator so those repos are weighted as
+// fully the user's own.
+func getContributedRepos(ctx context.Context, graphqlClient *api.GraphQLClient, restClient *api.RESTClient, login string, since, until time.Time) ([]contributedRepo, error) {
+	repos, err := getContributedReposGraphQL(ctx, graphqlClient, login, since, until)
+	if err == nil {
+		return repos, nil
+	}
+	return searchContributedRepos(ctx, restClient, login, since, until)
+}
+
+type contributionsCollectionResult struct {
+	User struct {
+		ContributionsCollection struct {
+			CommitContributionsByRepository []struct {
+				Repository struct {
+					NameWithOwner string `json:"nameWithOwner"`
+					Name          string `json:"name"`
+					Owner         struct {
+						Login string `json:"login"`
+					} `json:"owner"`
+				} `json:"repository"`
+				Contributions struct {
+					TotalCount int `json:"totalCount"`
+				} `json:"contributions"`
+			} `json:"commitContributionsByRepository"`
+		} `json:"contributionsCollection"`
+	} `json:"user"`
+}
+
+func getContributedReposGraphQL(ctx context.Context, client *api.GraphQLClient, login string, since, until time.Time) ([]contributedRepo, error) {
+	query := `
+		query($login: String!, $from: DateTime!, $to: DateTime!) {
+			user(login: $login) {
+				contributionsCollection(from: $from, to: $to) {
+					commitContributionsByRepository(maxRepositories: 100) {
+						repository { nameWithOwner name owner { login } }
+						contributions { totalCount }
+					}
+				}
+			}
+		}`
+	variables := map[string]interface{}{
+		"login": login,
+		"from":  since.Format(time.RFC3339),
+		"to":    until.Format(time.RFC3339),
+	}
+
+	var response contributionsCollectionResult
+	if err := client.DoWithContext(ctx, query, variables, &response); err != nil {
+		return nil, err
+	}
+
+	repos := make([]contributedRepo, 0, len(response.User.ContributionsCollection.CommitContributionsByRepository))
+	for _, entry := range response.User.ContributionsCollection.CommitContributionsByRepository {
+		repos = append(repos, contributedRepo{
+			Owner:       entry.Repository.Owner.Login,
+			Name:        entry.Repository.Name,
+			FullName:    entry.Repository.NameWithOwner,
+			UserCommits: entry.Contributions.TotalCount,
+		})
+	}
+	return repos, nil
+}
+
+// getTotalCommitCounts fills in TotalCommits for each repo by batching
+// `history(since:, until:) { totalCount }` lookups on the default branch,
+// the same aliasing strategy getLanguagesGraphQL uses for languages.
+func getTotalCommitCounts(ctx context.Context, client *api.GraphQLClient, repos []contributedRepo, since, until time.Time) ([]contributedRepo, error) {
+	for start := 0; start < len(repos); start += graphQLBatchSize {
+		end := start + graphQLBatchSize
+		if end > len(repos) {
+			end = len(repos)
+		}
+		batch := repos[start:end]
+
+		var b strings.Builder
+		b.WriteString("query {\n")
+		for i, repo := range batch {
+			fmt.Fprintf(&b, "  repo%d: repository(owner: %q, name: %q) {\n", i, repo.Owner, repo.Name)
+			b.WriteString("    defaultBranchRef {\n")
+			b.WriteString("      target {\n")
+			b.WriteString("        ... on Commit {\n")
+			fmt.Fprintf(&b, "          history(since: %q, until: %q) { totalCount }\n", since.Format(time.RFC3339), until.Format(time.RFC3339))
+			b.WriteString("        }\n")
+			b.WriteString("      }\n")
+			b.WriteString("    }\n")
+			b.WriteString("  }\n")
+		}
+		b.WriteString("}")
+
+		var response map[string]struct {
+			DefaultBranchRef struct {
+				Target struct {
+					History struct {
+						TotalCount int `json:"totalCount"`
+					} `json:"history"`
+				} `json:"target"`
+			} `json:"defaultBranchRef"`
+		}
+		if err := client.DoWithContext(ctx, b.String(), nil, &response); err != nil {
+			return nil, err
+		}
+
+		for i := range batch {
+			batch[i].TotalCommits = response[fmt.Sprintf("repo%d", i)].DefaultBranchRef.Target.History.TotalCount
+		}
+	}
+
+	return repos, nil
+}
+
+type commitSearchResult struct {
+	Items []struct {
+		Repository struct {
+			FullName string `json:"full_name"`
+			Name     string `json:"name"`
+			Owner    struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+	} `json:"items"`
+}
+
+// searchContributedRepos is the REST fallback for discovering contributed
+// repos: it counts commits per repo out of /search/commits?q=author:login.
+func searchContributedRepos(ctx context.Context, client *api.RESTClient, login string, since, until time.Time) ([]contributedRepo, error) {
+	query := fmt.Sprintf("author:%s committer-date:%s..%s", login, since.Format("2006-01-02"), until.Format("2006-01-02"))
+
+	commits := map[string]contributedRepo{}
+	page := 1
+	for {
+		path := fmt.Sprintf("search/commits?q=%s&per_page=100&page=%d", url.QueryEscape(query), page)
+		response, err := client.RequestWithContext(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result commitSearchResult
+		if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+			response.Body.Close()
+			return nil, err
+		}
+		response.Body.Close()
+
+		if len(result.Items) == 0 {
+			break
+		}
+
+		for _, item := range result.Items {
+			repo := commits[item.Repository.FullName]
+			repo.Owner = item.Repository.Owner.Login
+			repo.Name = item.Repository.Name
+			repo.FullName = item.Repository.FullName
+			repo.UserCommits++
+			commits[item.Repository.FullName] = repo
+		}
+		page++
+	}
+
+	repos := make([]contributedRepo, 0, len(commits))
+	for _, repo := range commits {
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// commitShare is the fraction of a repo's commits in the window that
+// belong to the account, used to weight its language byte counts instead
+// of crediting the whole repo to a single contributor.
+func (r contributedRepo) commitShare() float64 {
+	if r.TotalCommits <= 0 {
+		return 1
+	}
+	share := float64(r.UserCommits) / float64(r.TotalCommits)
+	if share > 1 {
+		return 1
+	}
+	return share
+}
+
+// weightLanguages scales each language's byte count by share, rounding to
+// the nearest byte.
+func weightLanguages(languages Languages, share float64) Languages {
+	weighted := make(Languages, len(languages))
+	for lang, bytes := range languages {
+		weighted[lang] = int(float64(bytes)*share + 0.5)
+	}
+	return weighted
+}
+
+// getContributionLanguages fetches language breakdowns for contributed
+// repos with the same bounded worker pool shape as getLanguages, weighting
+// each repo's bytes by the account's commitShare as it goes.
+func getContributionLanguages(ctx context.Context, client *api.RESTClient, repos []contributedRepo, concurrency int) (LanguagesList, error) {
+	return runWorkerPool(ctx, repos, concurrency, nil, func(ctx context.Context, repo contributedRepo) (RepoLanguages, error) {
+		response, err := client.RequestWithContext(ctx, http.MethodGet, fmt.Sprintf("repos/%s/languages", repo.FullName), nil)
+		if err != nil {
+			return RepoLanguages{}, err
+		}
+		defer response.Body.Close()
+
+		languages := Languages{}
+		if err := json.NewDecoder(response.Body).Decode(&languages); err != nil {
+			return RepoLanguages{}, err
+		}
+
+		return RepoLanguages{Repo: repo.FullName, Languages: weightLanguages(languages, repo.commitShare())}, nil
+	})
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+)
+
+// runDiff aggregates an account's languages over two windows and prints the
+// delta between them. Both windows default to a `filterVal`-years-ago..now
+// split in half, so `gh langs diff USER` works without any date flags.
+func runDiff(ctx context.Context, cfg Config, account, apiMode string, concurrency int, includeContributions bool, filterVal float64, sinceA, untilA, sinceB, untilB, outputPath string) error {
+	windowA, windowB, err := diffWindows(filterVal, sinceA, untilA, sinceB, untilB)
+	if err != nil {
+		return err
+	}
+
+	before, err := fetchAggregation(aggregateParams{
+		ctx:                  ctx,
+		cfg:                  cfg,
+		account:              account,
+		apiMode:              apiMode,
+		concurrency:          concurrency,
+		includeContributions: includeContributions,
+		since:                windowA.since,
+		until:                windowA.until,
+		useCache:             true,
+	})
+	if err != nil {
+		return err
+	}
+
+	after, err := fetchAggregation(aggregateParams{
+		ctx:                  ctx,
+		cfg:                  cfg,
+		account:              account,
+		apiMode:              apiMode,
+		concurrency:          concurrency,
+		includeContributions: includeContributions,
+		since:                windowB.since,
+		until:                windowB.until,
+		useCache:             true,
+	})
+	if err != nil {
+		return err
+	}
+
+	w, close, err := openOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	renderDiff(w, before.Languages, after.Languages)
+	return nil
+}
+
+type timeWindow struct{ since, until time.Time }
+
+// diffWindows resolves the two comparison windows from explicit flags, or
+// falls back to splitting the --filter lookback in half so the "older" half
+// is compared against the "newer" half.
+func diffWindows(filterVal float64, sinceA, untilA, sinceB, untilB string) (timeWindow, timeWindow, error) {
+	if filterVal == 0.0 {
+		filterVal = 1
+	}
+	midpoint := computeFilter(filterVal / 2)
+	start := computeFilter(filterVal)
+	now := time.Now()
+
+	a := timeWindow{since: start, until: midpoint}
+	b := timeWindow{since: midpoint, until: now}
+
+	var err error
+	if a.since, err = parseDateFlag(sinceA, a.since); err != nil {
+		return a, b, err
+	}
+	if a.until, err = parseDateFlag(untilA, a.until); err != nil {
+		return a, b, err
+	}
+	if b.since, err = parseDateFlag(sinceB, b.since); err != nil {
+		return a, b, err
+	}
+	if b.until, err = parseDateFlag(untilB, b.until); err != nil {
+		return a, b, err
+	}
+	return a, b, nil
+}
+
+func parseDateFlag(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// renderDiff prints a table of added/removed lines and percent change per
+// language, for every language present in either window.
+func renderDiff(w io.Writer, before, after Languages) {
+	langs := map[string]bool{}
+	for lang := range before {
+		langs[lang] = true
+	}
+	for lang := range after {
+		langs[lang] = true
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"Language", "Before", "After", "Delta", "% Change"})
+
+	for _, pair := range sortedPairs(after) {
+		lang := pair.Key
+		before, after := before[lang], after[lang]
+		delta := after - before
+		t.AppendRow(table.Row{lang, before, after, formatDelta(delta), formatPercent(before, delta)})
+		delete(langs, lang)
+	}
+	dropped := make([]string, 0, len(langs))
+	for lang := range langs {
+		dropped = append(dropped, lang)
+	}
+	sort.Strings(dropped)
+	for _, lang := range dropped {
+		before, after := before[lang], after[lang]
+		delta := after - before
+		t.AppendRow(table.Row{lang, before, after, formatDelta(delta), formatPercent(before, delta)})
+	}
+
+	t.Render()
+}
+
+func formatDelta(delta int) string {
+	if delta > 0 {
+		return fmt.Sprintf("+%d", delta)
+	}
+	return fmt.Sprintf("%d", delta)
+}
+
+func formatPercent(before int, delta int) string {
+	if before == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", float64(delta)/float64(before)*100)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/google/go-github/v61/github"
+)
+
+// graphQLBatchSize is how many repository aliases are folded into a single
+// GraphQL query. GitHub's GraphQL API charges a node-count-based cost per
+// query, so batching keeps us well under the per-call limit while still
+// cutting a thousand-repo org down to a couple dozen round trips.
+const graphQLBatchSize = 50
+
+type languagesQueryResult map[string]struct {
+	Languages struct {
+		Edges []struct {
+			Size int `json:"size"`
+			Node struct {
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"languages"`
+}
+
+// getLanguagesGraphQL fetches per-repo language breakdowns in batches of
+// graphQLBatchSize via a single aliased `repository(owner:, name:)` query
+// per batch, instead of one REST call per repo.
+func getLanguagesGraphQL(ctx context.Context, client *api.GraphQLClient, data []github.Repository) (LanguagesList, error) {
+	results := make(LanguagesList, 0, len(data))
+
+	for start := 0; start < len(data); start += graphQLBatchSize {
+		end := start + graphQLBatchSize
+		if end > len(data) {
+			end = len(data)
+		}
+		batch := data[start:end]
+
+		query, aliases := buildLanguagesQuery(batch)
+
+		var response languagesQueryResult
+		if err := client.DoWithContext(ctx, query, nil, &response); err != nil {
+			return nil, err
+		}
+
+		for i, alias := range aliases {
+			languages := Languages{}
+			for _, edge := range response[alias].Languages.Edges {
+				languages[edge.Node.Name] = edge.Size
+			}
+			results = append(results, RepoLanguages{Repo: batch[i].GetFullName(), Languages: languages})
+		}
+	}
+
+	return results, nil
+}
+
+// buildLanguagesQuery renders one GraphQL document containing an aliased
+// `repository` field per repo in the batch, and returns the aliases in the
+// same order so callers can line results back up with the input repos.
+func buildLanguagesQuery(repos []github.Repository) (string, []string) {
+	var b strings.Builder
+	aliases := make([]string, 0, len(repos))
+
+	b.WriteString("query {\n")
+	for i, repo := range repos {
+		alias := fmt.Sprintf("repo%d", i)
+		aliases = append(aliases, alias)
+
+		fmt.Fprintf(&b, "  %s: repository(owner: %q, name: %q) {\n", alias, repo.GetOwner().GetLogin(), repo.GetName())
+		b.WriteString("    languages(first: 20) {\n")
+		b.WriteString("      edges { size node { name } }\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}")
+
+	return b.String(), aliases
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// cachedResponse is a stored copy of a prior 200 response, keyed by request
+// URL, that lets us revalidate with If-None-Match instead of re-downloading
+// a repo's language breakdown every run.
+type cachedResponse struct {
+	ETag       string      `json:"etag"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// etagCachingTransport is an httpcache-style http.RoundTripper: it adds
+// If-None-Match to outgoing GET requests based on a previously seen ETag,
+// and replays the cached body whenever the server answers 304 Not Modified
+// so a 304 never costs rate-limit budget beyond the conditional request
+// itself. Entries are persisted to disk (see etagCachePath) so the cache
+// actually survives between CLI invocations rather than just within one.
+type etagCachingTransport struct {
+	base    http.RoundTripper
+	path    string
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	dirty   bool
+}
+
+// etagCachePath returns the on-disk location of the persisted ETag cache,
+// alongside the account snapshots cache.go keeps under ~/.cache/gh-langs.
+func etagCachePath() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "etag-cache.json"), nil
+}
+
+func newETagCachingTransport(base http.RoundTripper) *etagCachingTransport {
+	t := &etagCachingTransport{base: base, entries: map[string]*cachedResponse{}}
+	if path, err := etagCachePath(); err == nil {
+		t.path = path
+		if data, err := os.ReadFile(path); err == nil {
+			_ = json.Unmarshal(data, &t.entries)
+		}
+	}
+	return t
+}
+
+// flush persists any entries learned during this run so the next invocation
+// can revalidate them with If-None-Match instead of downloading fresh.
+func (t *etagCachingTransport) flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.dirty || t.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(t.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(t.path, data, 0o644); err != nil {
+		return err
+	}
+	t.dirty = false
+	return nil
+}
+
+func (t *etagCachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+
+	t.mu.Lock()
+	entry := t.entries[key]
+	t.mu.Unlock()
+
+	if entry != nil && entry.ETag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		return &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Proto:      resp.Proto,
+			ProtoMajor: resp.ProtoMajor,
+			ProtoMinor: resp.ProtoMinor,
+			Header:     entry.Header,
+			Body:       io.NopCloser(bytes.NewReader(entry.Body)),
+			Request:    resp.Request,
+		}, nil
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return resp, err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+
+			t.mu.Lock()
+			t.entries[key] = &cachedResponse{
+				ETag:       etag,
+				StatusCode: resp.StatusCode,
+				Header:     resp.Header,
+				Body:       body,
+			}
+			t.dirty = true
+			t.mu.Unlock()
+		}
+	}
+
+	return resp, nil
+}
@@ -2,32 +2,60 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"sort"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/fatih/color"
 	"github.com/google/go-github/v61/github"
-	"github.com/jedib0t/go-pretty/table"
-	"github.com/jedib0t/go-pretty/text"
 	"github.com/yanskun/pflag"
-	"golang.org/x/text/message"
 )
 
+// subcommands are the dispatcher's known verbs. A first positional arg
+// that isn't one of these is treated as an account name and the legacy
+// `gh langs <account>` invocation runs summary, unchanged.
+var subcommands = map[string]bool{"summary": true, "diff": true, "trend": true}
+
 func main() {
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	var filterVal float64
 	var helpFlag bool
+	var apiMode string
+	var concurrency int
+	var profileName string
+	var formatVal string
+	var outputPath string
+	var includeContributions bool
+	var sinceA, untilA, sinceB, untilB string
+	var buckets int
 	pflag.BoolVarP(&helpFlag, "help", "h", false, "Show help for command")
-	pflag.Float64VarP(&filterVal, "filter", "f", 1.0, "Filter past by float value in years (e.g. 0.5)")
+	pflag.Float64VarP(&filterVal, "filter", "f", cfg.Filter, "Filter past by float value in years (e.g. 0.5)")
+	pflag.StringVar(&apiMode, "api", "rest", "API strategy to fetch languages with: rest|graphql")
+	pflag.IntVar(&concurrency, "concurrency", cfg.Concurrency, "Number of repos to fetch languages for concurrently")
+	pflag.StringVar(&profileName, "profile", "", "Named profile from config.yaml to apply")
+	pflag.StringVarP(&formatVal, "format", "o", cfg.Format, "Output format: table|json|csv|markdown|svg")
+	pflag.StringVar(&outputPath, "output", "", "Write output to this file instead of stdout")
+	pflag.BoolVar(&includeContributions, "include-contributions", false, "Also weigh in languages from external repos committed to during the filter window")
+	pflag.StringVar(&sinceA, "since", "", "diff: start of the older window (YYYY-MM-DD, defaults to 2x --filter years ago)")
+	pflag.StringVar(&untilA, "until", "", "diff: end of the older window (YYYY-MM-DD, defaults to --filter years ago)")
+	pflag.StringVar(&sinceB, "since2", "", "diff: start of the newer window (YYYY-MM-DD, defaults to --filter years ago)")
+	pflag.StringVar(&untilB, "until2", "", "diff: end of the newer window (YYYY-MM-DD, defaults to now)")
+	pflag.IntVar(&buckets, "buckets", 12, "trend: number of monthly samples to take")
 	pflag.Parse()
 
 	if helpFlag {
@@ -35,44 +63,130 @@ func main() {
 		return
 	}
 
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Start()
+	subcommand := "summary"
+	accountArg := pflag.Arg(0)
+	if subcommands[accountArg] {
+		subcommand = accountArg
+		accountArg = pflag.Arg(1)
+	}
+
+	var profile Profile
+	if profileName != "" {
+		var ok bool
+		profile, ok = cfg.Profiles[profileName]
+		if !ok {
+			fmt.Printf("Unknown profile %q\n", profileName)
+			return
+		}
+		cfg = applyProfile(cfg, profile)
+		if !pflag.Lookup("filter").Changed {
+			filterVal = cfg.Filter
+		}
+		if !pflag.Lookup("concurrency").Changed {
+			concurrency = cfg.Concurrency
+		}
+		if !pflag.Lookup("format").Changed {
+			formatVal = cfg.Format
+		}
+	}
+
+	if apiMode != "rest" && apiMode != "graphql" {
+		fmt.Printf("Invalid --api value %q, must be rest or graphql\n", apiMode)
+		return
+	}
+	if concurrency < 1 {
+		fmt.Println("--concurrency must be at least 1")
+		return
+	}
 
-	account := pflag.Arg(0)
+	account := accountArg
+	if account == "" {
+		account = profile.Account
+	}
 	if account == "" {
 		account, _ = getGitHubUsername()
 	}
 
-	client, err := api.DefaultRESTClient()
-	if err != nil {
-		return
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var runErr error
+	switch subcommand {
+	case "diff":
+		runErr = runDiff(ctx, cfg, account, apiMode, concurrency, includeContributions, filterVal, sinceA, untilA, sinceB, untilB, outputPath)
+	case "trend":
+		runErr = runTrend(ctx, cfg, account, apiMode, concurrency, includeContributions, buckets, formatVal, outputPath)
+	default:
+		runErr = runSummary(ctx, cfg, account, apiMode, concurrency, includeContributions, filterVal, formatVal, outputPath)
+	}
+	if runErr != nil {
+		fmt.Println(runErr)
 	}
+}
 
-	repos, err := getRepositories(client, account)
+// runSummary is the original single-window aggregation: fetch an account's
+// repos, sum their languages, and render them in formatVal.
+func runSummary(ctx context.Context, cfg Config, account, apiMode string, concurrency int, includeContributions bool, filterVal float64, formatVal, outputPath string) error {
+	output, err := newOutput(formatVal)
 	if err != nil {
-		return
+		return err
 	}
 
-	var filter time.Time
+	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+	s.Start()
+
+	var since time.Time
 	if filterVal != 0.0 {
-		filter = computeFilter(filterVal)
-		repos = filterRepositories(repos, filter)
+		since = computeFilter(filterVal)
 	}
 
-	results, err := getLanguages(client, repos)
+	result, err := fetchAggregation(aggregateParams{
+		ctx:                  ctx,
+		cfg:                  cfg,
+		account:              account,
+		apiMode:              apiMode,
+		concurrency:          concurrency,
+		includeContributions: includeContributions,
+		since:                since,
+		onProgress: func(done, total int) {
+			s.Suffix = fmt.Sprintf(" fetched %d/%d repos", done, total)
+		},
+	})
+	s.Stop()
 	if err != nil {
-		return
+		return err
 	}
 
-	languages := sumLanguages(results)
+	w, close, err := openOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	defer close()
 
-	s.Stop()
+	if err := output.Render(w, result.Languages, result.Repos, result.RateLimit); err != nil {
+		return err
+	}
 
-	printTable(languages)
-	fmt.Printf("https:github.com/%s has %d repositories\n", account, len(repos))
-	if filterVal != 0.0 {
-		fmt.Printf("Last updated after %s\n", filter.Format("2006-01-02"))
+	if formatVal == "table" {
+		fmt.Printf("https:github.com/%s has %d repositories\n", account, result.RepoCount)
+		if filterVal != 0.0 {
+			fmt.Printf("Last updated after %s\n", since.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
+
+// openOutput returns os.Stdout, or a freshly created file if path is
+// non-empty, plus a close func that's safe to defer unconditionally.
+func openOutput(path string) (io.Writer, func(), error) {
+	if path == "" {
+		return os.Stdout, func() {}, nil
 	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close() }, nil
 }
 
 func printHelp() {
@@ -80,7 +194,9 @@ func printHelp() {
 	b.Println("\nUSAGE")
 	fmt.Println("  gh langs <command> [options]\n")
 	b.Println("COMMANDS")
-	fmt.Println("  account:  Get languages used by a GitHub user or organization\n")
+	fmt.Println("  summary:  Get languages used by a GitHub user or organization (default)")
+	fmt.Println("  diff:     Compare language usage between two time windows")
+	fmt.Println("  trend:    Sample language usage over time as a sparkline or CSV\n")
 	b.Println("OPTIONS")
 	pflag.Usage()
 	return
@@ -131,7 +247,7 @@ func findAccountType(client *api.RESTClient, account string) (string, error) {
 	return "", fmt.Errorf("Unknown account type: %s", data.GetType())
 }
 
-func getRepositories(client *api.RESTClient, account string) ([]github.Repository, error) {
+func getRepositories(ctx context.Context, client *api.RESTClient, account string) ([]github.Repository, error) {
 	var repos []github.Repository
 	page := 1
 
@@ -143,7 +259,7 @@ func getRepositories(client *api.RESTClient, account string) ([]github.Repositor
 
 	for {
 		url := fmt.Sprintf("%s?per_page=100&page=%d", endpoint, page)
-		response, err := client.Request(http.MethodGet, url, nil)
+		response, err := client.RequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
 			fmt.Printf("%s is not a valid GitHub username\n", account)
 			return nil, err
@@ -168,63 +284,79 @@ func getRepositories(client *api.RESTClient, account string) ([]github.Repositor
 	return repos, nil
 }
 
-func filterRepositories(repos []github.Repository, filter time.Time) []github.Repository {
+func filterRepositories(repos []github.Repository, since, until time.Time, excludeRepos []string, includeForks bool) []github.Repository {
 	var results []github.Repository
 	for _, repo := range repos {
-		if repo.GetUpdatedAt().After(filter) {
-			results = append(results, repo)
+		if !includeForks && repo.GetFork() {
+			continue
+		}
+		updatedAt := repo.GetUpdatedAt().Time
+		if !since.IsZero() && !updatedAt.After(since) {
+			continue
+		}
+		if !until.IsZero() && updatedAt.After(until) {
+			continue
+		}
+		if matchesAny(excludeRepos, repo.GetName()) || matchesAny(excludeRepos, repo.GetFullName()) {
+			continue
 		}
+		results = append(results, repo)
 	}
 	return results
 }
 
 type (
-	Languages     map[string]int
-	LanguagesList []Languages
-)
-
-func getLanguages(client *api.RESTClient, data []github.Repository) (LanguagesList, error) {
-	results := make(LanguagesList, 0, len(data))
-
-	var wg sync.WaitGroup
-
-	for _, repo := range data {
-		wg.Add(1)
-		go func(repo github.Repository) {
-			defer wg.Done()
+	Languages map[string]int
+
+	// RepoLanguages pairs a repo's full name with its language breakdown,
+	// so output formats that need per-repo detail (e.g. json) don't have
+	// to re-derive it from the aggregated totals.
+	RepoLanguages struct {
+		Repo      string
+		Languages Languages
+	}
 
-			fullName := repo.GetFullName()
-			response, err := client.Request(http.MethodGet, fmt.Sprintf("repos/%s/languages", fullName), nil)
-			if err != nil {
-				log.Fatal(err)
-				return
-			}
+	LanguagesList []RepoLanguages
+)
 
-			decoder := json.NewDecoder(response.Body)
-			data := Languages{}
-			if err := decoder.Decode(&data); err != nil {
-				log.Fatal(err)
-				return
-			}
+// getLanguages fetches each repo's language breakdown using a bounded pool
+// of concurrency workers (see runWorkerPool). The first per-repo error
+// cancels ctx so the remaining in-flight requests abort instead of racing
+// to completion, and onProgress (if non-nil) is called once per completed
+// repo so callers can drive a progress indicator.
+func getLanguages(ctx context.Context, client *api.RESTClient, data []github.Repository, concurrency int, onProgress func(done, total int)) (LanguagesList, error) {
+	return runWorkerPool(ctx, data, concurrency, onProgress, func(ctx context.Context, repo github.Repository) (RepoLanguages, error) {
+		languages, err := fetchLanguages(ctx, client, repo)
+		if err != nil {
+			return RepoLanguages{}, err
+		}
+		return RepoLanguages{Repo: repo.GetFullName(), Languages: languages}, nil
+	})
+}
 
-			if err := response.Body.Close(); err != nil {
-				log.Fatal(err)
-				return
-			}
+func fetchLanguages(ctx context.Context, client *api.RESTClient, repo github.Repository) (Languages, error) {
+	response, err := client.RequestWithContext(ctx, http.MethodGet, fmt.Sprintf("repos/%s/languages", repo.GetFullName()), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
 
-			results = append(results, data)
-		}(repo)
+	languages := Languages{}
+	if err := json.NewDecoder(response.Body).Decode(&languages); err != nil {
+		return nil, err
 	}
-	wg.Wait()
 
-	return results, nil
+	return languages, nil
 }
 
-func sumLanguages(list LanguagesList) Languages {
+func sumLanguages(list LanguagesList, excludeLanguages []string) Languages {
 	results := Languages{}
 
-	for _, languages := range list {
-		for lang, lines := range languages {
+	for _, repoLanguages := range list {
+		for lang, lines := range repoLanguages.Languages {
+			if matchesAny(excludeLanguages, lang) {
+				continue
+			}
 			results[lang] += lines
 		}
 	}
@@ -237,49 +369,17 @@ type Pair struct {
 	Value int
 }
 
-func printTable(languages Languages) {
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	p := message.NewPrinter(message.MatchLanguage("en"))
-	t.AppendHeader(table.Row{"Language", "Lines"})
-
-	// Convert map to slice
+// sortedPairs flattens languages into a slice sorted by line count,
+// descending, for output formats that render an ordered list.
+func sortedPairs(languages Languages) []Pair {
 	pairs := make([]Pair, 0, len(languages))
 	for k, v := range languages {
 		pairs = append(pairs, Pair{k, v})
 	}
-
-	// Sort slice in descending order by Value
 	sort.Slice(pairs, func(i, j int) bool {
 		return pairs[i].Value > pairs[j].Value
 	})
-
-	t.SetColumnConfigs([]table.ColumnConfig{
-		{
-			Name:  "Language",
-			Align: text.AlignLeft,
-		},
-		{
-			Name:  "Lines",
-			Align: text.AlignRight,
-			Transformer: func(val interface{}) string {
-				return p.Sprintf("%d", val)
-			},
-			TransformerFooter: func(val interface{}) string {
-				return p.Sprintf("%d", val)
-			},
-		},
-	})
-
-	// Append rows in sorted order
-	sumLines := 0
-	for _, pair := range pairs {
-		sumLines += pair.Value
-		t.AppendRow(table.Row{pair.Key, pair.Value})
-	}
-
-	t.AppendFooter(table.Row{"Total", sumLines})
-	t.Render()
+	return pairs
 }
 
 // For more examples of using go-gh, see:
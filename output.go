@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/jedib0t/go-pretty/text"
+	"golang.org/x/text/message"
+)
+
+// Output renders an aggregation result to w in a specific format. repos
+// carries the per-repo breakdown alongside the aggregated languages, for
+// formats (like json) that expose it.
+type Output interface {
+	Render(w io.Writer, languages Languages, repos LanguagesList, rateLimit *rateLimitStatus) error
+}
+
+// newOutput resolves a --format name to its Output implementation.
+func newOutput(format string) (Output, error) {
+	switch format {
+	case "table":
+		return tableOutput{}, nil
+	case "json":
+		return jsonOutput{}, nil
+	case "csv":
+		return csvOutput{}, nil
+	case "markdown":
+		return markdownOutput{}, nil
+	case "svg":
+		return svgOutput{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, must be one of table, json, csv, markdown, svg", format)
+	}
+}
+
+func newLanguagesTable(languages Languages) table.Writer {
+	t := table.NewWriter()
+	p := message.NewPrinter(message.MatchLanguage("en"))
+	t.AppendHeader(table.Row{"Language", "Lines"})
+
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{
+			Name:  "Language",
+			Align: text.AlignLeft,
+		},
+		{
+			Name:  "Lines",
+			Align: text.AlignRight,
+			Transformer: func(val interface{}) string {
+				return p.Sprintf("%d", val)
+			},
+			TransformerFooter: func(val interface{}) string {
+				return p.Sprintf("%d", val)
+			},
+		},
+	})
+
+	sumLines := 0
+	for _, pair := range sortedPairs(languages) {
+		sumLines += pair.Value
+		t.AppendRow(table.Row{pair.Key, pair.Value})
+	}
+	t.AppendFooter(table.Row{"Total", sumLines})
+
+	return t
+}
+
+type tableOutput struct{}
+
+func (tableOutput) Render(w io.Writer, languages Languages, repos LanguagesList, rateLimit *rateLimitStatus) error {
+	t := newLanguagesTable(languages)
+	t.SetOutputMirror(w)
+	t.Render()
+
+	if limit, remaining, ok := rateLimit.snapshot(); ok {
+		fmt.Fprintf(w, "API rate limit: %d/%d remaining\n", remaining, limit)
+	}
+	return nil
+}
+
+type csvOutput struct{}
+
+// Render writes raw, locale-independent integers via encoding/csv directly,
+// rather than reusing newLanguagesTable: that table's Lines column runs
+// through a message.Printer transformer for human-readable grouping
+// (e.g. "1,000"), and go-pretty's RenderCSV then backslash-escapes the
+// embedded comma instead of quoting it, producing a non-standard cell no
+// ordinary CSV parser reads back as an integer.
+func (csvOutput) Render(w io.Writer, languages Languages, repos LanguagesList, rateLimit *rateLimitStatus) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"Language", "Lines"}); err != nil {
+		return err
+	}
+
+	sumLines := 0
+	for _, pair := range sortedPairs(languages) {
+		sumLines += pair.Value
+		if err := writer.Write([]string{pair.Key, strconv.Itoa(pair.Value)}); err != nil {
+			return err
+		}
+	}
+	if err := writer.Write([]string{"Total", strconv.Itoa(sumLines)}); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+type markdownOutput struct{}
+
+func (markdownOutput) Render(w io.Writer, languages Languages, repos LanguagesList, rateLimit *rateLimitStatus) error {
+	_, err := fmt.Fprintln(w, newLanguagesTable(languages).RenderMarkdown())
+	return err
+}
+
+// jsonReport is the shape of the --format json output: aggregated totals
+// plus the per-repo breakdown they were summed from.
+type jsonReport struct {
+	Languages Languages            `json:"languages"`
+	Repos     map[string]Languages `json:"repos"`
+}
+
+type jsonOutput struct{}
+
+func (jsonOutput) Render(w io.Writer, languages Languages, repos LanguagesList, rateLimit *rateLimitStatus) error {
+	report := jsonReport{
+		Languages: languages,
+		Repos:     make(map[string]Languages, len(repos)),
+	}
+	for _, repo := range repos {
+		report.Repos[repo.Repo] = repo.Languages
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// svgChartTopN bounds how many languages get a bar in the SVG chart, to
+// keep it readable for accounts that touch dozens of languages.
+const svgChartTopN = 15
+
+const (
+	svgBarHeight  = 24
+	svgBarGap     = 6
+	svgLabelWidth = 160
+	svgChartWidth = 640
+)
+
+type svgOutput struct{}
+
+func (svgOutput) Render(w io.Writer, languages Languages, repos LanguagesList, rateLimit *rateLimitStatus) error {
+	pairs := sortedPairs(languages)
+	if len(pairs) > svgChartTopN {
+		pairs = pairs[:svgChartTopN]
+	}
+
+	maxValue := 0
+	for _, pair := range pairs {
+		if pair.Value > maxValue {
+			maxValue = pair.Value
+		}
+	}
+
+	height := len(pairs)*(svgBarHeight+svgBarGap) + svgBarGap
+	barAreaWidth := svgChartWidth - svgLabelWidth
+
+	fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"12\">\n", svgChartWidth, height)
+	for i, pair := range pairs {
+		y := svgBarGap + i*(svgBarHeight+svgBarGap)
+		barWidth := 0
+		if maxValue > 0 {
+			barWidth = pair.Value * barAreaWidth / maxValue
+		}
+
+		fmt.Fprintf(w, "  <text x=\"0\" y=\"%d\" dominant-baseline=\"middle\">%s</text>\n", y+svgBarHeight/2, pair.Key)
+		fmt.Fprintf(w, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"#2b90d9\" />\n", svgLabelWidth, y, barWidth, svgBarHeight)
+		fmt.Fprintf(w, "  <text x=\"%d\" y=\"%d\" dominant-baseline=\"middle\">%d</text>\n", svgLabelWidth+barWidth+6, y+svgBarHeight/2, pair.Value)
+	}
+	fmt.Fprintln(w, "</svg>")
+
+	return nil
+}
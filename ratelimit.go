@@ -0,0 +1,138 @@
+package main
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitStatus is a thread-safe snapshot of the most recently observed
+// GitHub API rate-limit headers, shared across every request a client makes.
+type rateLimitStatus struct {
+	mu        sync.Mutex
+	limit     int
+	remaining int
+	reset     time.Time
+	seen      bool
+}
+
+func newRateLimitStatus() *rateLimitStatus {
+	return &rateLimitStatus{}
+}
+
+func (r *rateLimitStatus) update(h http.Header) {
+	limit, okLimit := parseIntHeader(h, "X-RateLimit-Limit")
+	remaining, okRemaining := parseIntHeader(h, "X-RateLimit-Remaining")
+	if !okLimit && !okRemaining {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if okLimit {
+		r.limit = limit
+	}
+	if okRemaining {
+		r.remaining = remaining
+	}
+	if resetVal := h.Get("X-RateLimit-Reset"); resetVal != "" {
+		if sec, err := strconv.ParseInt(resetVal, 10, 64); err == nil {
+			r.reset = time.Unix(sec, 0)
+		}
+	}
+	r.seen = true
+}
+
+// snapshot returns the last observed limit/remaining values. ok is false if
+// no response carrying rate-limit headers has been seen yet.
+func (r *rateLimitStatus) snapshot() (limit, remaining int, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.limit, r.remaining, r.seen
+}
+
+func parseIntHeader(h http.Header, key string) (int, bool) {
+	val := h.Get(key)
+	if val == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// rateLimitTransport wraps a base RoundTripper, recording rate-limit headers
+// on every response and backing off with jittered retries when the budget
+// is exhausted (HTTP 403/429 with X-RateLimit-Remaining: 0, or any response
+// carrying Retry-After).
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	status  *rateLimitStatus
+	retries int
+}
+
+func newRateLimitTransport(base http.RoundTripper, status *rateLimitStatus) *rateLimitTransport {
+	return &rateLimitTransport{base: base, status: status, retries: 3}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+
+		t.status.update(resp.Header)
+
+		wait, shouldRetry := retryDelay(resp)
+		if !shouldRetry || attempt >= t.retries {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay inspects a response for signs the caller should back off:
+// an exhausted rate limit or an explicit Retry-After header. It returns a
+// delay with a few hundred milliseconds of jitter added to avoid every
+// in-flight goroutine waking up at the same instant.
+func retryDelay(resp *http.Response) (time.Duration, bool) {
+	jitter := time.Duration(rand.Intn(500)) * time.Millisecond
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if sec, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(sec)*time.Second + jitter, true
+		}
+	}
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+
+	resetSec, ok := parseIntHeader(resp.Header, "X-RateLimit-Reset")
+	if !ok {
+		return 0, false
+	}
+	wait := time.Until(time.Unix(int64(resetSec), 0))
+	if wait < 0 {
+		wait = 0
+	}
+	return wait + jitter, true
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// sparkTicks are the block characters used to render a trend sparkline,
+// from lowest to highest.
+var sparkTicks = []rune(" ▁▂▃▄▅▆▇█")
+
+// runTrend samples language usage in `buckets` monthly windows over the
+// last year (reusing cached snapshots per bucket) and renders either an
+// ASCII sparkline per language or a CSV suitable for plotting.
+func runTrend(ctx context.Context, cfg Config, account, apiMode string, concurrency int, includeContributions bool, buckets int, formatVal, outputPath string) error {
+	if buckets < 2 {
+		return fmt.Errorf("--buckets must be at least 2")
+	}
+
+	now := time.Now()
+	start := now.AddDate(-1, 0, 0)
+	step := now.Sub(start) / time.Duration(buckets)
+
+	series := map[string][]int{}
+	order := make([]string, 0)
+
+	for i := 0; i < buckets; i++ {
+		bucketSince := start.Add(step * time.Duration(i))
+		bucketUntil := start.Add(step * time.Duration(i+1))
+
+		result, err := fetchAggregation(aggregateParams{
+			ctx:                  ctx,
+			cfg:                  cfg,
+			account:              account,
+			apiMode:              apiMode,
+			concurrency:          concurrency,
+			includeContributions: includeContributions,
+			since:                bucketSince,
+			until:                bucketUntil,
+			useCache:             true,
+		})
+		if err != nil {
+			return err
+		}
+
+		for lang := range result.Languages {
+			if _, seen := series[lang]; !seen {
+				series[lang] = make([]int, i)
+				order = append(order, lang)
+			}
+		}
+		for _, lang := range order {
+			series[lang] = append(series[lang], result.Languages[lang])
+		}
+	}
+
+	sort.Strings(order)
+
+	w, close, err := openOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	if formatVal == "csv" {
+		return renderTrendCSV(w, order, series, buckets)
+	}
+	renderTrendSparklines(w, order, series)
+	return nil
+}
+
+func renderTrendSparklines(w io.Writer, order []string, series map[string][]int) {
+	for _, lang := range order {
+		fmt.Fprintf(w, "%-20s %s\n", lang, sparkline(series[lang]))
+	}
+}
+
+func renderTrendCSV(w io.Writer, order []string, series map[string][]int, buckets int) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	header := make([]string, 0, buckets+1)
+	header = append(header, "language")
+	for i := 0; i < buckets; i++ {
+		header = append(header, fmt.Sprintf("bucket%d", i+1))
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, lang := range order {
+		row := make([]string, 0, buckets+1)
+		row = append(row, lang)
+		for _, value := range series[lang] {
+			row = append(row, fmt.Sprintf("%d", value))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sparkline renders values as a row of block characters scaled to the
+// series' own max, so each language's sparkline uses its full height.
+func sparkline(values []int) string {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	ticks := make([]rune, len(values))
+	for i, v := range values {
+		if max == 0 {
+			ticks[i] = sparkTicks[0]
+			continue
+		}
+		level := v * (len(sparkTicks) - 1) / max
+		ticks[i] = sparkTicks[level]
+	}
+	return string(ticks)
+}
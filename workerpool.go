@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// runWorkerPool fans item out across a bounded pool of concurrency workers,
+// calling fetch for each and collecting the results in completion order.
+// The first error from fetch cancels the shared context so in-flight and
+// not-yet-started work stops promptly, and onProgress (if non-nil) is
+// called after every completed item. This is the common shape behind
+// getLanguages and getContributionLanguages: a job channel, a
+// mutex-free result channel, and sync.Once-guarded first-error
+// propagation instead of log.Fatal inside a goroutine.
+func runWorkerPool[T, R any](ctx context.Context, items []T, concurrency int, onProgress func(done, total int), fetch func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan T)
+	resultsCh := make(chan R, len(items))
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				result, err := fetch(ctx, item)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+				resultsCh <- result
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, item := range items {
+			select {
+			case jobs <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	results := make([]R, 0, len(items))
+	done := 0
+	for result := range resultsCh {
+		results = append(results, result)
+		done++
+		if onProgress != nil {
+			onProgress(done, len(items))
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
